@@ -0,0 +1,37 @@
+package pulsar
+
+import "time"
+
+// subscriptionChunkManager is the subscription-scoped counterpart to chunkedMsgCtxMap. A single
+// instance is owned by the consumer, regexConsumer or multiTopicConsumer that represents a
+// subscription and shared by every partitionConsumer underneath it, so that chunks of a message
+// produced to a partitioned topic reassemble correctly no matter which partition (and therefore
+// which partitionConsumer) each one lands on, and so MaxPendingChunkedMessage /
+// ExpireTimeOfIncompleteChunk apply once across the whole subscription rather than once per
+// partition.
+//
+// Entries are keyed by (producerName, uuid) rather than uuid alone: two different producers
+// publishing to different partitions of the same topic may reuse chunk UUIDs independently.
+type subscriptionChunkManager struct {
+	*chunkedMsgCtxMap
+}
+
+func newSubscriptionChunkManager(maxPending int, expireTime time.Duration, store ChunkStore) *subscriptionChunkManager {
+	return &subscriptionChunkManager{chunkedMsgCtxMap: newChunkedMsgCtxMap(maxPending, expireTime, store)}
+}
+
+func chunkKey(producerName, uuid string) string {
+	return producerName + "/" + uuid
+}
+
+func (m *subscriptionChunkManager) addIfAbsent(producerName, uuid string, numChunksFromMsg int) *chunkedMsgCtx {
+	return m.chunkedMsgCtxMap.addIfAbsent(chunkKey(producerName, uuid), numChunksFromMsg)
+}
+
+func (m *subscriptionChunkManager) get(producerName, uuid string) *chunkedMsgCtx {
+	return m.chunkedMsgCtxMap.get(chunkKey(producerName, uuid))
+}
+
+func (m *subscriptionChunkManager) remove(producerName, uuid string) {
+	m.chunkedMsgCtxMap.remove(chunkKey(producerName, uuid))
+}
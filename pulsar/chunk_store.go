@@ -0,0 +1,92 @@
+package pulsar
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ChunkStore persists the chunks of an in-flight chunked message as they arrive and assembles
+// them into the final payload once complete. Implementations must be safe for concurrent use,
+// since chunks of different messages may be stored concurrently.
+type ChunkStore interface {
+	// Put stores chunkID's payload for the chunked message identified by uuid.
+	Put(uuid string, chunkID int, payload []byte) error
+
+	// Assemble returns a reader over the full, in-order payload for uuid. It is only called
+	// once every chunk for uuid has been stored.
+	Assemble(uuid string) (io.ReadCloser, error)
+
+	// Discard drops any state held for uuid, e.g. because the message was delivered, its
+	// transaction aborted, or it expired incomplete.
+	Discard(uuid string)
+}
+
+// memoryChunkStore is the default ChunkStore, buffering chunks in memory. It preserves the
+// behavior of the original, store-less implementation.
+type memoryChunkStore struct {
+	mu     sync.Mutex
+	chunks map[string]map[int][]byte
+}
+
+// NewMemoryChunkStore creates a ChunkStore that buffers chunks in memory.
+func NewMemoryChunkStore() ChunkStore {
+	return &memoryChunkStore{chunks: make(map[string]map[int][]byte)}
+}
+
+func (s *memoryChunkStore) Put(uuid string, chunkID int, payload []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	buf, ok := s.chunks[uuid]
+	if !ok {
+		buf = make(map[int][]byte)
+		s.chunks[uuid] = buf
+	}
+	buf[chunkID] = append([]byte(nil), payload...)
+	return nil
+}
+
+func (s *memoryChunkStore) Assemble(uuid string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	buf, ok := s.chunks[uuid]
+	if !ok {
+		return nil, errChunkNotFound(uuid)
+	}
+
+	var out bytes.Buffer
+	for i := 0; i < len(buf); i++ {
+		payload, ok := buf[i]
+		if !ok {
+			return nil, errChunkMissing(uuid, i)
+		}
+		out.Write(payload)
+	}
+	return io.NopCloser(&out), nil
+}
+
+func (s *memoryChunkStore) Discard(uuid string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.chunks, uuid)
+}
+
+type chunkNotFoundError string
+
+func (e chunkNotFoundError) Error() string { return "no chunks buffered for uuid " + string(e) }
+
+func errChunkNotFound(uuid string) error { return chunkNotFoundError(uuid) }
+
+type chunkMissingError struct {
+	uuid    string
+	chunkID int
+}
+
+func (e chunkMissingError) Error() string {
+	return fmt.Sprintf("uuid %s is missing chunk %d", e.uuid, e.chunkID)
+}
+
+func errChunkMissing(uuid string, chunkID int) error { return chunkMissingError{uuid, chunkID} }
@@ -0,0 +1,84 @@
+package pulsar
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileChunkStoreAssemble(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chunk-store")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := NewFileChunkStore(dir)
+	uuid := "assemble-uuid"
+
+	assert.NoError(t, store.Put(uuid, 1, []byte("world")))
+	assert.NoError(t, store.Put(uuid, 0, []byte("hello ")))
+
+	reader, err := store.Assemble(uuid)
+	assert.NoError(t, err)
+	defer reader.Close()
+
+	data, err := ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", string(data))
+}
+
+// TestFileChunkStoreAssembleFailsOnGap guards against silently short-reading a message that's
+// missing a chunk: with only chunk 0 and chunk 2 on disk (chunk 1 never arrived), Assemble must
+// error out instead of concatenating the two chunks it has into a truncated payload.
+func TestFileChunkStoreAssembleFailsOnGap(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chunk-store")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := NewFileChunkStore(dir)
+	uuid := "gap-uuid"
+
+	assert.NoError(t, store.Put(uuid, 0, []byte("hello ")))
+	assert.NoError(t, store.Put(uuid, 2, []byte("world")))
+
+	_, err = store.Assemble(uuid)
+	assert.Error(t, err)
+}
+
+// TestMemoryChunkStoreAssembleFailsOnGap is TestFileChunkStoreAssembleFailsOnGap's counterpart
+// for the default in-memory store.
+func TestMemoryChunkStoreAssembleFailsOnGap(t *testing.T) {
+	store := NewMemoryChunkStore()
+	uuid := "gap-uuid"
+
+	assert.NoError(t, store.Put(uuid, 0, []byte("hello ")))
+	assert.NoError(t, store.Put(uuid, 2, []byte("world")))
+
+	_, err := store.Assemble(uuid)
+	assert.Error(t, err)
+}
+
+func TestExpireIncompleteChunksDiscardsFromFileChunkStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chunk-store")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := NewFileChunkStore(dir)
+	chunkCtxMap := newChunkedMsgCtxMap(0, time.Millisecond*300, store)
+
+	uuid := "expire-uuid"
+	chunkCtxMap.addIfAbsent(uuid, 2)
+	assert.NoError(t, store.Put(uuid, 0, []byte("partial")))
+
+	_, err = os.Stat(filepath.Join(dir, uuid))
+	assert.NoError(t, err)
+
+	time.Sleep(400 * time.Millisecond)
+
+	assert.Nil(t, chunkCtxMap.get(uuid))
+	_, err = os.Stat(filepath.Join(dir, uuid))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,109 @@
+package pulsar
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Gleiphir2769/pulsar-client-go/pulsar/internal"
+)
+
+// ClientOptions specifies the options for creating a Client.
+type ClientOptions struct {
+	// URL is the connection string for the Pulsar service, e.g. "pulsar://localhost:6650".
+	URL string
+
+	// ConnectionTimeout bounds how long the client waits to establish a broker connection.
+	ConnectionTimeout time.Duration
+}
+
+// Client represents a connection to a Pulsar cluster, used to create producers, consumers and
+// readers against it.
+type Client interface {
+	// CreateProducer creates a new producer on the given topic.
+	CreateProducer(ProducerOptions) (Producer, error)
+
+	// Subscribe creates a new consumer according to the given options. The consumer attaches
+	// to options.Topic or to every topic in options.Topics, depending on which is set. When
+	// options.TopicsPattern is set, Subscribe returns an error: topics matching a pattern are
+	// not looked up.
+	Subscribe(ConsumerOptions) (Consumer, error)
+
+	// SubscribePattern is Subscribe with TopicsPattern set to pattern, and so returns that
+	// same error.
+	SubscribePattern(pattern string, options ConsumerOptions) (Consumer, error)
+
+	// NewTransaction begins a new transaction with the transaction coordinator, which will be
+	// automatically aborted if it isn't committed within timeout.
+	NewTransaction(timeout time.Duration) (Transaction, error)
+
+	// Close releases all resources associated with the client.
+	Close()
+}
+
+type client struct {
+	options  ClientOptions
+	rpcPool  *internal.ConnectionPool
+	tcClient *internal.TransactionCoordinatorClient
+}
+
+// NewClient creates a Client with the given options.
+func NewClient(options ClientOptions) (Client, error) {
+	if options.URL == "" {
+		return nil, errors.New("URL is required for creating a client")
+	}
+
+	c := &client{
+		options: options,
+		rpcPool: internal.NewConnectionPool(),
+	}
+	c.tcClient = internal.NewTransactionCoordinatorClient(c.rpcPool)
+
+	return c, nil
+}
+
+func (c *client) CreateProducer(options ProducerOptions) (Producer, error) {
+	return newPartitionProducer(c, options)
+}
+
+func (c *client) Subscribe(options ConsumerOptions) (Consumer, error) {
+	switch {
+	case options.TopicsPattern != "":
+		topics, err := c.lookupTopicsMatching(options.TopicsPattern)
+		if err != nil {
+			return nil, err
+		}
+		return newRegexConsumer(c, options.TopicsPattern, topics, options)
+	case len(options.Topics) > 0:
+		return newMultiTopicConsumer(c, options.Topics, options)
+	default:
+		return newConsumer(c, options)
+	}
+}
+
+func (c *client) SubscribePattern(pattern string, options ConsumerOptions) (Consumer, error) {
+	options.TopicsPattern = pattern
+	return c.Subscribe(options)
+}
+
+func (c *client) lookupTopicsMatching(pattern string) ([]string, error) {
+	// TODO: ask the broker's topic lookup service for every topic matching pattern, the same
+	// way CreateProducer/Subscribe eventually look up a topic's own partitions. Until this
+	// exists, SubscribePattern itself can't be exercised end-to-end; see
+	// TestSubscribePatternFailsUntilTopicLookupExists and, for the cross-partition chunk
+	// reassembly that's independent of this lookup,
+	// TestRegexConsumerReassemblesChunksAcrossPartitions.
+	return nil, fmt.Errorf("topic pattern discovery for %q is not yet implemented", pattern)
+}
+
+func (c *client) NewTransaction(timeout time.Duration) (Transaction, error) {
+	txnID, err := c.tcClient.NewTransaction(timeout)
+	if err != nil {
+		return nil, err
+	}
+	return newTransaction(txnID, c.tcClient, timeout), nil
+}
+
+func (c *client) Close() {
+	c.rpcPool.Close()
+}
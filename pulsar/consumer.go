@@ -0,0 +1,76 @@
+package pulsar
+
+import (
+	"context"
+	"time"
+)
+
+// SubscriptionType defines how messages are dispatched to consumers sharing the same subscription.
+type SubscriptionType int
+
+const (
+	// Exclusive only allows a single consumer to attach to the subscription.
+	Exclusive SubscriptionType = iota
+
+	// Shared dispatches messages round-robin across all attached consumers.
+	Shared
+
+	// Failover lets a single consumer at a time receive, with standby failover consumers.
+	Failover
+
+	// KeyShared dispatches messages with the same key to the same consumer.
+	KeyShared
+)
+
+// ConsumerOptions specifies the options for creating a Consumer.
+type ConsumerOptions struct {
+	// Topic to subscribe to. Mutually exclusive with Topics/TopicsPattern.
+	Topic string
+
+	// Topics is a fixed set of topics to subscribe to as a single logical consumer.
+	Topics []string
+
+	// TopicsPattern is a regular expression naming the topics to subscribe to. Topics matching
+	// it are not looked up: Subscribe returns an error when it is set.
+	TopicsPattern string
+
+	// SubscriptionName identifies the subscription.
+	SubscriptionName string
+
+	// Type selects the subscription dispatch semantics.
+	Type SubscriptionType
+
+	// MaxPendingChunkedMessage bounds how many incomplete chunked messages are buffered
+	// before the oldest one is discarded.
+	MaxPendingChunkedMessage int
+
+	// ExpireTimeOfIncompleteChunk is how long an incomplete chunked message is kept before
+	// being discarded. Zero selects the implementation default.
+	ExpireTimeOfIncompleteChunk time.Duration
+
+	// ChunkStore persists the chunks of in-flight chunked messages as they arrive. When nil,
+	// an in-memory store is used, matching the original buffering behavior.
+	ChunkStore ChunkStore
+}
+
+// Consumer provides a high level API for consuming messages from a Pulsar topic.
+type Consumer interface {
+	// Subscription returns the subscription name for this consumer.
+	Subscription() string
+
+	// Receive blocks until a message is available, ctx is done, or the consumer is closed.
+	Receive(ctx context.Context) (Message, error)
+
+	// Ack acknowledges a single message.
+	Ack(Message) error
+
+	// AckID acknowledges a single message by ID.
+	AckID(MessageID) error
+
+	// AckWithTxn acknowledges msg as part of txn: the acknowledgement only becomes visible
+	// once txn is committed, and is rolled back if txn is aborted instead.
+	AckWithTxn(msg Message, txn Transaction) error
+
+	// Close releases all resources associated with this consumer.
+	Close()
+}
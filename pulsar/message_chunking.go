@@ -0,0 +1,136 @@
+package pulsar
+
+import (
+	"sync"
+	"time"
+)
+
+const defaultExpireTimeOfIncompleteChunk = time.Minute
+
+// chunkedMsgCtx tracks the chunks received so far for a single chunked message, identified by
+// its producer-assigned UUID. Chunk payloads themselves are not held here: they are written
+// through to the owning chunkedMsgCtxMap's ChunkStore as they arrive. receivedChunkIDs tracks
+// which distinct chunkIDs have been seen rather than just a count, so a chunk redelivered after a
+// producer reconnect (see partitionProducer.resumeOrAbortChunkedSend) doesn't make the message
+// look complete before every distinct chunk has actually arrived.
+type chunkedMsgCtx struct {
+	totalChunks      int
+	receivedChunkIDs map[int]bool
+	lastChunkedMsgID MessageID
+	receivedTime     time.Time
+}
+
+func newChunkedMsgCtx(numChunksFromMsg int) *chunkedMsgCtx {
+	return &chunkedMsgCtx{
+		totalChunks:      numChunksFromMsg,
+		receivedChunkIDs: make(map[int]bool),
+		receivedTime:     time.Now(),
+	}
+}
+
+func (c *chunkedMsgCtx) received(chunkID int, msgID MessageID) {
+	c.receivedChunkIDs[chunkID] = true
+	c.lastChunkedMsgID = msgID
+}
+
+func (c *chunkedMsgCtx) complete() bool {
+	return len(c.receivedChunkIDs) == c.totalChunks
+}
+
+// chunkedMsgCtxMap keeps the in-flight reassembly state for all chunked messages currently
+// pending on a subscription (see subscriptionChunkManager), bounding memory usage via maxPending and expiring stale
+// entries that never complete. Chunk bytes are persisted through store rather than buffered
+// here, so very large messages don't require holding the whole payload in the process' heap.
+type chunkedMsgCtxMap struct {
+	sync.Mutex
+	maxPending       int
+	expireTime       time.Duration
+	store            ChunkStore
+	chunkedMsgCtxMap map[string]*chunkedMsgCtx
+	pendingQueue     []string
+}
+
+func newChunkedMsgCtxMap(maxPending int, expireTime time.Duration, store ChunkStore) *chunkedMsgCtxMap {
+	if expireTime <= 0 {
+		expireTime = defaultExpireTimeOfIncompleteChunk
+	}
+	if store == nil {
+		store = NewMemoryChunkStore()
+	}
+	return &chunkedMsgCtxMap{
+		maxPending:       maxPending,
+		expireTime:       expireTime,
+		store:            store,
+		chunkedMsgCtxMap: make(map[string]*chunkedMsgCtx),
+	}
+}
+
+// addIfAbsent creates reassembly state for uuid if it doesn't already exist, evicting the
+// oldest pending chunked message if maxPending would be exceeded, and schedules its expiry.
+func (c *chunkedMsgCtxMap) addIfAbsent(uuid string, numChunksFromMsg int) *chunkedMsgCtx {
+	c.Lock()
+	defer c.Unlock()
+
+	if ctx, ok := c.chunkedMsgCtxMap[uuid]; ok {
+		return ctx
+	}
+
+	if c.maxPending > 0 && len(c.pendingQueue) >= c.maxPending {
+		oldest := c.pendingQueue[0]
+		c.pendingQueue = c.pendingQueue[1:]
+		delete(c.chunkedMsgCtxMap, oldest)
+		c.store.Discard(oldest)
+	}
+
+	ctx := newChunkedMsgCtx(numChunksFromMsg)
+	c.chunkedMsgCtxMap[uuid] = ctx
+	c.pendingQueue = append(c.pendingQueue, uuid)
+
+	expireTime := c.expireTime
+	time.AfterFunc(expireTime, func() {
+		c.expireIfStale(uuid, expireTime)
+	})
+
+	return ctx
+}
+
+func (c *chunkedMsgCtxMap) expireIfStale(uuid string, expireTime time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+
+	ctx, ok := c.chunkedMsgCtxMap[uuid]
+	if !ok {
+		return
+	}
+	if time.Since(ctx.receivedTime) >= expireTime {
+		c.removeLocked(uuid)
+	}
+}
+
+// get returns the reassembly state for uuid, or nil if it isn't pending (never seen, already
+// completed, or expired/discarded).
+func (c *chunkedMsgCtxMap) get(uuid string) *chunkedMsgCtx {
+	c.Lock()
+	defer c.Unlock()
+	return c.chunkedMsgCtxMap[uuid]
+}
+
+// remove discards the reassembly state for uuid, e.g. once the message has been fully
+// reassembled and delivered, or its transaction aborted. The underlying ChunkStore is told to
+// discard its bytes for uuid too, so disk/memory isn't leaked.
+func (c *chunkedMsgCtxMap) remove(uuid string) {
+	c.Lock()
+	defer c.Unlock()
+	c.removeLocked(uuid)
+}
+
+func (c *chunkedMsgCtxMap) removeLocked(uuid string) {
+	delete(c.chunkedMsgCtxMap, uuid)
+	for i, id := range c.pendingQueue {
+		if id == uuid {
+			c.pendingQueue = append(c.pendingQueue[:i], c.pendingQueue[i+1:]...)
+			break
+		}
+	}
+	c.store.Discard(uuid)
+}
@@ -0,0 +1,133 @@
+package pulsar
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"time"
+)
+
+// partitionConsumer consumes messages from a single topic partition. Chunk reassembly state is
+// not owned here: it lives in a subscriptionChunkManager shared across every partitionConsumer
+// of the owning consumer/regexConsumer/multiTopicConsumer, so chunks of one producer's message
+// that land on different partitions of a partitioned topic still reassemble correctly.
+type partitionConsumer struct {
+	topic        string
+	subscription string
+
+	options      ConsumerOptions
+	chunkManager *subscriptionChunkManager
+
+	messageCh chan Message
+}
+
+func newPartitionConsumer(topic, subscription string, options ConsumerOptions, chunkManager *subscriptionChunkManager) *partitionConsumer {
+	return &partitionConsumer{
+		topic:        topic,
+		subscription: subscription,
+		options:      options,
+		chunkManager: chunkManager,
+		messageCh:    make(chan Message, 1000),
+	}
+}
+
+// messageReceived is invoked for every raw message the broker delivers on this partition,
+// including each chunk of a chunked message. producerName identifies the chunk's originating
+// producer, since its uuid is only unique per-producer. Non-chunked messages are delivered
+// directly; chunks are written through to the shared chunkManager's ChunkStore and only
+// assembled into a delivered Message once every chunk for their (producerName, uuid) has
+// arrived.
+func (pc *partitionConsumer) messageReceived(producerName, uuid string, chunkID, numChunks int, payload []byte, msgID MessageID, aborted bool) (Message, bool) {
+	if uuid == "" {
+		return pc.newMessage(msgID, payload), true
+	}
+
+	if aborted {
+		// The producer's transaction aborted mid-chunk: drop whatever partial state we have
+		// for this (producerName, uuid) so it doesn't sit around until it expires on its own.
+		pc.chunkManager.remove(producerName, uuid)
+		return nil, false
+	}
+
+	ctx := pc.chunkManager.addIfAbsent(producerName, uuid, numChunks)
+	storeKey := chunkKey(producerName, uuid)
+	if err := pc.chunkManager.store.Put(storeKey, chunkID, payload); err != nil {
+		pc.chunkManager.remove(producerName, uuid)
+		return nil, false
+	}
+	ctx.received(chunkID, msgID)
+	if !ctx.complete() {
+		return nil, false
+	}
+
+	reader, err := pc.chunkManager.store.Assemble(storeKey)
+	pc.chunkManager.remove(producerName, uuid)
+	if err != nil {
+		return nil, false
+	}
+
+	return &chunkedMessage{id: ctx.lastChunkedMsgID, topic: pc.topic, reader: reader}, true
+}
+
+func (pc *partitionConsumer) newMessage(id MessageID, payload []byte) Message {
+	return &message{id: id, payload: payload, topic: pc.topic}
+}
+
+// ackWithTxn acknowledges msg as part of txn instead of immediately, registering the
+// (topic, subscription) pair with the transaction coordinator so the ack only becomes visible
+// once txn commits.
+func (pc *partitionConsumer) ackWithTxn(msg Message, txn Transaction) error {
+	if txn == nil {
+		return errors.New("transaction is required")
+	}
+	if err := txn.registerAck(pc.topic, pc.subscription); err != nil {
+		return err
+	}
+
+	// TODO: send the ACK command carrying txn's TxnID to the broker instead of the
+	// unconditional ack used outside of a transaction.
+	return nil
+}
+
+// message is a non-chunked message, whose payload was received in a single broker SEND.
+type message struct {
+	id      MessageID
+	payload []byte
+	topic   string
+}
+
+func (m *message) Topic() string                 { return m.topic }
+func (m *message) Properties() map[string]string { return nil }
+func (m *message) Payload() []byte               { return m.payload }
+func (m *message) PayloadReader() io.ReadCloser  { return ioutil.NopCloser(bytes.NewReader(m.payload)) }
+func (m *message) ID() MessageID                 { return m.id }
+func (m *message) PublishTime() time.Time        { return time.Time{} }
+func (m *message) Key() string                   { return "" }
+func (m *message) OrderingKey() string           { return "" }
+
+// chunkedMessage is a message reassembled from a ChunkStore. Payload() materializes the whole
+// message into memory; PayloadReader() streams it instead, which matters once the backing store
+// is file-based.
+type chunkedMessage struct {
+	id     MessageID
+	topic  string
+	reader io.ReadCloser
+}
+
+func (m *chunkedMessage) Topic() string                 { return m.topic }
+func (m *chunkedMessage) Properties() map[string]string { return nil }
+func (m *chunkedMessage) ID() MessageID                 { return m.id }
+func (m *chunkedMessage) PublishTime() time.Time        { return time.Time{} }
+func (m *chunkedMessage) Key() string                   { return "" }
+func (m *chunkedMessage) OrderingKey() string           { return "" }
+
+func (m *chunkedMessage) Payload() []byte {
+	defer m.reader.Close()
+	b, _ := ioutil.ReadAll(m.reader)
+	return b
+}
+
+func (m *chunkedMessage) PayloadReader() io.ReadCloser {
+	return m.reader
+}
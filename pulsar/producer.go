@@ -0,0 +1,64 @@
+package pulsar
+
+import "context"
+
+// ProducerOptions specifies the options for creating a producer.
+type ProducerOptions struct {
+	// Topic the producer will publish to.
+	Topic string
+
+	// Name is an optional, explicit producer name. If not set, the client auto-generates one.
+	Name string
+
+	// DisableBatching disables automatic batching of outgoing messages.
+	DisableBatching bool
+
+	// MaxPendingMessages caps the number of messages waiting for a broker ack before Send
+	// blocks (or fails, see DisableBlockIfQueueFull).
+	MaxPendingMessages int
+
+	// DisableBlockIfQueueFull makes Send/SendAsync return an error immediately instead of
+	// blocking when MaxPendingMessages is reached.
+	DisableBlockIfQueueFull bool
+
+	// EnableChunking splits messages larger than the broker's max message size (or
+	// ChunkMaxMessageSize, if set) into multiple chunks that are reassembled by the consumer.
+	// It cannot be combined with batching.
+	EnableChunking bool
+
+	// ChunkMaxMessageSize overrides the per-chunk payload size. When zero, the broker's
+	// configured max message size is used.
+	ChunkMaxMessageSize int
+}
+
+// Producer provides a high level API for publishing messages to a Pulsar topic.
+type Producer interface {
+	// Topic returns the topic this producer is publishing to.
+	Topic() string
+
+	// Name returns this producer's name.
+	Name() string
+
+	// Send publishes a message and waits for acknowledgement from the broker.
+	Send(context.Context, *ProducerMessage) (MessageID, error)
+
+	// SendAsync publishes a message and invokes callback once the broker has acknowledged it,
+	// or on failure.
+	SendAsync(context.Context, *ProducerMessage, func(MessageID, *ProducerMessage, error))
+
+	// SendChunkedAsync publishes msg through the chunking path, invoking callback once per
+	// chunk as it is dispatched to the broker (requires EnableChunking; otherwise callback
+	// fires once for the whole message). Returning a non-nil error from callback stops the
+	// producer from enqueuing any remaining chunks, and that error is delivered through one
+	// final callback invocation with chunkIndex totalChunks-1 and a nil id.
+	SendChunkedAsync(ctx context.Context, msg *ProducerMessage, callback func(chunkIndex, totalChunks int, id MessageID, err error) error)
+
+	// LastSequenceID returns the sequence ID of the last message queued for sending.
+	LastSequenceID() int64
+
+	// Flush blocks until all pending messages are sent.
+	Flush() error
+
+	// Close releases all resources associated with this producer.
+	Close()
+}
@@ -0,0 +1,82 @@
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendChunkedAsyncReportsPerChunkProgress(t *testing.T) {
+	payload := createTestMessagePayload(230)
+
+	producer, err := newPartitionProducer(nil, ProducerOptions{
+		Topic:               "chunk-async-topic",
+		DisableBatching:     true,
+		EnableChunking:      true,
+		ChunkMaxMessageSize: 50,
+	})
+	assert.NoError(t, err)
+	defer producer.Close()
+
+	var mu sync.Mutex
+	seen := 0
+	done := make(chan struct{})
+	producer.SendChunkedAsync(context.Background(), &ProducerMessage{Payload: payload},
+		func(chunkIndex, totalChunks int, id MessageID, err error) error {
+			mu.Lock()
+			seen++
+			isLast := chunkIndex == totalChunks-1
+			mu.Unlock()
+			if isLast {
+				close(done)
+			}
+			return nil
+		})
+
+	<-done
+	assert.Equal(t, 5, seen)
+}
+
+func TestSendChunkedAsyncCancelMidSend(t *testing.T) {
+	producer, err := newPartitionProducer(nil, ProducerOptions{
+		Topic:               "chunk-async-topic",
+		DisableBatching:     true,
+		EnableChunking:      true,
+		ChunkMaxMessageSize: 50,
+	})
+	assert.NoError(t, err)
+	defer producer.Close()
+
+	cancelErr := errors.New("caller gave up on this send")
+
+	var mu sync.Mutex
+	chunksSeen := 0
+	var finalErr error
+	done := make(chan struct{})
+	producer.SendChunkedAsync(context.Background(), &ProducerMessage{Payload: createTestMessagePayload(230)},
+		func(chunkIndex, totalChunks int, id MessageID, err error) error {
+			mu.Lock()
+			chunksSeen++
+			n := chunksSeen
+			mu.Unlock()
+
+			if err != nil {
+				finalErr = err
+				close(done)
+				return nil
+			}
+			if n == 2 {
+				return cancelErr
+			}
+			return nil
+		})
+
+	<-done
+	assert.Equal(t, cancelErr, finalErr)
+	assert.Less(t, chunksSeen, 5)
+	assert.Empty(t, producer.(*partitionProducer).inFlight,
+		"a send the caller cancelled must not be resumed on a later reconnect")
+}
@@ -0,0 +1,327 @@
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+const defaultBrokerMaxMessageSize = 1024 * 1024
+
+// chunkSendState tracks an in-flight chunked send so that, if the broker connection is lost
+// partway through, partitionProducer can resume from the last acknowledged chunk using the same
+// uuid and sequenceID range (so broker-side deduplication collapses any chunk that made it out
+// before the disconnect) instead of restarting the whole message from chunk zero.
+//
+// mu is held for the entire duration of whichever goroutine is currently sending chunks for this
+// uuid, whether that's the original sendChunked loop or a resumeOrAbortChunkedSend triggered by
+// connectionReestablished, so the two can never send overlapping chunks concurrently.
+type chunkSendState struct {
+	mu sync.Mutex
+
+	uuid            string
+	msg             *ProducerMessage
+	startSequenceID int64
+	totalChunks     int
+	lastAckedChunk  int // -1 until the first chunk of this uuid has been acked
+}
+
+// chunkRelay delivers chunked-send events to whatever is consuming them, standing in for the
+// broker passing chunks (and an abort marker) on to a consumer. In this sandbox there is no
+// broker to relay through, so wiring this up is how the behavior described in
+// sendAbortChunkMarker and sendOneChunk's doc comments is actually testable end-to-end.
+type chunkRelay struct {
+	onChunk func(producerName, uuid string, chunkID, numChunks int, payload []byte)
+	onAbort func(producerName, uuid string)
+}
+
+// partitionProducer implements Producer against a single topic partition.
+type partitionProducer struct {
+	options ProducerOptions
+	topic   string
+	name    string
+
+	maxMessageSize int
+	pendingQueue   chan struct{}
+
+	seqMu          sync.Mutex
+	lastSequenceID int64
+
+	inFlightMu sync.Mutex
+	inFlight   map[string]*chunkSendState
+
+	// relay delivers chunk/abort events to a consumer in tests; nil in production until a real
+	// broker wire path exists (see sendOneChunk/sendAbortChunkMarker).
+	relay *chunkRelay
+
+	// simulateConnectionDrop, when set, makes internalSingleSend fail for a given chunkID instead
+	// of succeeding, standing in for a broker connection dropping mid chunked-send. Tests use it
+	// to exercise resumeOrAbortChunkedSend without a real broker to disconnect from; nil in
+	// production.
+	simulateConnectionDrop func(chunkID int) error
+}
+
+func newPartitionProducer(client *client, options ProducerOptions) (Producer, error) {
+	if options.Topic == "" {
+		return nil, errors.New("topic is required")
+	}
+	if options.EnableChunking && !options.DisableBatching {
+		return nil, errors.New("chunking can not be enabled when batching is enabled")
+	}
+
+	maxMessageSize := defaultBrokerMaxMessageSize
+	if options.EnableChunking && options.ChunkMaxMessageSize > 0 && options.ChunkMaxMessageSize < maxMessageSize {
+		maxMessageSize = options.ChunkMaxMessageSize
+	}
+
+	maxPending := options.MaxPendingMessages
+	if maxPending <= 0 {
+		maxPending = 1000
+	}
+
+	p := &partitionProducer{
+		options:        options,
+		topic:          options.Topic,
+		name:           options.Name,
+		maxMessageSize: maxMessageSize,
+		pendingQueue:   make(chan struct{}, maxPending),
+		inFlight:       make(map[string]*chunkSendState),
+	}
+	return p, nil
+}
+
+func (p *partitionProducer) Topic() string { return p.topic }
+func (p *partitionProducer) Name() string  { return p.name }
+
+func (p *partitionProducer) Send(ctx context.Context, msg *ProducerMessage) (MessageID, error) {
+	var (
+		id  MessageID
+		err error
+	)
+	done := make(chan struct{})
+	p.SendAsync(ctx, msg, func(mid MessageID, _ *ProducerMessage, e error) {
+		id, err = mid, e
+		close(done)
+	})
+	<-done
+	return id, err
+}
+
+func (p *partitionProducer) SendAsync(ctx context.Context, msg *ProducerMessage, callback func(MessageID, *ProducerMessage, error)) {
+	p.sendChunked(ctx, msg, func(chunkIndex, totalChunks int, id MessageID, err error) error {
+		if chunkIndex == totalChunks-1 || err != nil {
+			callback(id, msg, err)
+		}
+		return nil
+	})
+}
+
+func (p *partitionProducer) SendChunkedAsync(ctx context.Context, msg *ProducerMessage, callback func(chunkIndex, totalChunks int, id MessageID, err error) error) {
+	p.sendChunked(ctx, msg, callback)
+}
+
+// nextSequenceRange reserves count consecutive sequence IDs and returns the first one, so a
+// chunked message's chunks occupy a contiguous, predictable range that can be replayed
+// unchanged on resend.
+func (p *partitionProducer) nextSequenceRange(count int) int64 {
+	p.seqMu.Lock()
+	defer p.seqMu.Unlock()
+	start := p.lastSequenceID + 1
+	p.lastSequenceID += int64(count)
+	return start
+}
+
+// sendChunked is the common chunking engine used by Send/SendAsync and by
+// Producer.SendChunkedAsync: it splits msg.Payload across one or more broker sends capped at
+// maxMessageSize, sharing a single UUID and sequenceID range (and, when msg.Transaction is set,
+// a single TxnID) across all chunks, and reports progress through progress as each chunk is
+// dispatched. If progress returns a non-nil error for a chunk that isn't the last one, sending
+// stops there and that error is reported again as the terminal (totalChunks-1) invocation.
+func (p *partitionProducer) sendChunked(ctx context.Context, msg *ProducerMessage, progress func(chunkIndex, totalChunks int, id MessageID, err error) error) {
+	if !p.options.EnableChunking || len(msg.Payload) <= p.maxMessageSize {
+		if len(msg.Payload) > defaultBrokerMaxMessageSize && !p.options.EnableChunking {
+			progress(0, 1, nil, fmt.Errorf("message size %d exceeds the max allowed size of %d and chunking is disabled",
+				len(msg.Payload), defaultBrokerMaxMessageSize))
+			return
+		}
+		if msg.Transaction != nil {
+			if err := msg.Transaction.registerSend(p.topic); err != nil {
+				progress(0, 1, nil, err)
+				return
+			}
+		}
+		seqID := p.nextSequenceRange(1)
+		id, err := p.internalSingleSend(ctx, msg.Payload, msg, "", 0, 1, seqID)
+		progress(0, 1, id, err)
+		return
+	}
+
+	chunkUUID := uuid.New().String()
+	totalChunks := (len(msg.Payload) + p.maxMessageSize - 1) / p.maxMessageSize
+
+	if msg.Transaction != nil {
+		if err := msg.Transaction.registerSend(p.topic); err != nil {
+			progress(0, totalChunks, nil, err)
+			return
+		}
+	}
+
+	startSeqID := p.nextSequenceRange(totalChunks)
+	state := &chunkSendState{
+		uuid:            chunkUUID,
+		msg:             msg,
+		startSequenceID: startSeqID,
+		totalChunks:     totalChunks,
+		lastAckedChunk:  -1,
+	}
+	p.inFlightMu.Lock()
+	p.inFlight[chunkUUID] = state
+	p.inFlightMu.Unlock()
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	for i := 0; i < totalChunks; i++ {
+		id, err := p.sendOneChunk(ctx, state, i)
+		if err != nil {
+			// A send itself failed, which in this tree only happens the way a dropped broker
+			// connection would (see internalSingleSend/simulateConnectionDrop): leave the uuid in
+			// p.inFlight so connectionReestablished can resume it from state.lastAckedChunk
+			// instead of restarting the whole message.
+			progress(i, totalChunks, nil, err)
+			return
+		}
+		if cbErr := progress(i, totalChunks, id, nil); cbErr != nil && i != totalChunks-1 {
+			// The caller itself aborted the send by returning an error from progress: that's a
+			// deliberate "stop sending" decision, not a transport failure, so there's nothing to
+			// resume on reconnect and the uuid must not linger in p.inFlight.
+			p.inFlightMu.Lock()
+			delete(p.inFlight, chunkUUID)
+			p.inFlightMu.Unlock()
+			progress(totalChunks-1, totalChunks, nil, cbErr)
+			return
+		}
+	}
+
+	p.inFlightMu.Lock()
+	delete(p.inFlight, chunkUUID)
+	p.inFlightMu.Unlock()
+}
+
+func (p *partitionProducer) sendOneChunk(ctx context.Context, state *chunkSendState, chunkID int) (MessageID, error) {
+	start := chunkID * p.maxMessageSize
+	end := start + p.maxMessageSize
+	if end > len(state.msg.Payload) {
+		end = len(state.msg.Payload)
+	}
+
+	payload := state.msg.Payload[start:end]
+	id, err := p.internalSingleSend(ctx, payload, state.msg, state.uuid, chunkID, state.totalChunks,
+		state.startSequenceID+int64(chunkID))
+	if err == nil {
+		state.lastAckedChunk = chunkID
+		if p.relay != nil && p.relay.onChunk != nil {
+			p.relay.onChunk(p.name, state.uuid, chunkID, state.totalChunks, payload)
+		}
+	}
+	return id, err
+}
+
+// internalSingleSend enqueues a single broker-level SEND for one chunk (or the whole message,
+// when chunkUUID is empty) of msg at the given sequenceID, honoring MaxPendingMessages/
+// DisableBlockIfQueueFull and attaching msg.Transaction's TxnID so the broker associates the
+// send with it.
+func (p *partitionProducer) internalSingleSend(ctx context.Context, payload []byte, msg *ProducerMessage, chunkUUID string, chunkID, numChunks int, sequenceID int64) (MessageID, error) {
+	if p.simulateConnectionDrop != nil {
+		if err := p.simulateConnectionDrop(chunkID); err != nil {
+			return nil, err
+		}
+	}
+
+	select {
+	case p.pendingQueue <- struct{}{}:
+	default:
+		if p.options.DisableBlockIfQueueFull {
+			return nil, errors.New("producer send queue is full")
+		}
+		select {
+		case p.pendingQueue <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	defer func() { <-p.pendingQueue }()
+
+	// TODO: serialize payload + metadata (chunkUUID/chunkID/numChunks, sequenceID, and
+	// msg.Transaction's TxnID when set) into a SEND command and write it to the broker
+	// connection for this partition; the broker ack completes this call with the assigned
+	// MessageID. Resending sequenceID unchanged (see connectionReestablished) lets the broker's
+	// deduplication cursor collapse a chunk that was acked just before the connection dropped.
+	return nil, nil
+}
+
+// connectionReestablished is invoked once a new broker connection for this partition is ready
+// after a disconnect, and resumes every chunked send that was still in flight. Because each
+// chunk's sequenceID was already reserved up front, resuming from lastAckedChunk+1 with the
+// same uuid and sequence range lets the broker's deduplication collapse any chunk that was
+// acked right before the connection dropped, rather than producing a duplicate partial message.
+func (p *partitionProducer) connectionReestablished() {
+	p.inFlightMu.Lock()
+	inFlight := make([]*chunkSendState, 0, len(p.inFlight))
+	for _, s := range p.inFlight {
+		inFlight = append(inFlight, s)
+	}
+	p.inFlightMu.Unlock()
+
+	for _, state := range inFlight {
+		p.resumeOrAbortChunkedSend(state)
+	}
+}
+
+func (p *partitionProducer) resumeOrAbortChunkedSend(state *chunkSendState) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	for i := state.lastAckedChunk + 1; i < state.totalChunks; i++ {
+		if _, err := p.sendOneChunk(context.Background(), state, i); err != nil {
+			// The resumed send failed again: tell the consumer to drop the partial uuid right
+			// away instead of leaving chunkedMsgCtxMap/subscriptionChunkManager to discover it
+			// only once ExpireTimeOfIncompleteChunk elapses.
+			p.sendAbortChunkMarker(state.uuid)
+			p.inFlightMu.Lock()
+			delete(p.inFlight, state.uuid)
+			p.inFlightMu.Unlock()
+			return
+		}
+	}
+
+	p.inFlightMu.Lock()
+	delete(p.inFlight, state.uuid)
+	p.inFlightMu.Unlock()
+}
+
+// sendAbortChunkMarker tells the broker (and, through it, the consumer) that uuid's chunked
+// message will never be completed, so chunkedMsgCtxMap/subscriptionChunkManager discards the
+// partial UUID immediately rather than waiting for it to expire.
+func (p *partitionProducer) sendAbortChunkMarker(uuid string) {
+	if p.relay != nil && p.relay.onAbort != nil {
+		p.relay.onAbort(p.name, uuid)
+		return
+	}
+	// TODO: send a dedicated abort-chunk command carrying uuid once the broker protocol
+	// supports it; until then the partial message is still cleaned up, just on the consumer's
+	// existing ExpireTimeOfIncompleteChunk timer instead of immediately.
+}
+
+func (p *partitionProducer) LastSequenceID() int64 {
+	p.seqMu.Lock()
+	defer p.seqMu.Unlock()
+	return p.lastSequenceID
+}
+
+func (p *partitionProducer) Flush() error { return nil }
+
+func (p *partitionProducer) Close() {}
@@ -0,0 +1,132 @@
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestChunkedSendResumesAfterReconnect simulates a broker connection dropping mid chunked-send
+// via simulateConnectionDrop, a test-only seam on partitionProducer that makes internalSingleSend
+// fail for one chunk (standing in for "the connection died") exactly the way a real transport
+// failure would, as opposed to the caller itself aborting the send through a progress callback
+// error (see TestSendChunkedAsyncCancelMidSend, which must NOT leave anything behind to resume).
+// The interrupted send leaves the partition producer's in-flight chunk state behind, and
+// connectionReestablished resumes it from the last acknowledged chunk using the same uuid and
+// sequence range rather than restarting the whole message. A chunkRelay wires the producer's
+// chunks straight to a partitionConsumer (standing in for the broker dispatching them), so this
+// test can assert directly on what the request asked for: the consumer ends up with exactly one
+// complete message equal to the original payload, and no orphaned uuid is left behind in
+// chunkedMsgCtxMap.
+func TestChunkedSendResumesAfterReconnect(t *testing.T) {
+	producer, err := newPartitionProducer(nil, ProducerOptions{
+		Topic:               "resend-topic",
+		Name:                "producer-1",
+		DisableBatching:     true,
+		EnableChunking:      true,
+		ChunkMaxMessageSize: 10,
+	})
+	assert.NoError(t, err)
+	pp := producer.(*partitionProducer)
+
+	mgr := newSubscriptionChunkManager(0, time.Minute, NewMemoryChunkStore())
+	consumer := newPartitionConsumer("resend-topic", "resend-subscriber", ConsumerOptions{}, mgr)
+
+	pp.relay = &chunkRelay{
+		onChunk: func(producerName, uuid string, chunkID, numChunks int, payload []byte) {
+			msg, done := consumer.messageReceived(producerName, uuid, chunkID, numChunks, payload, nil, false)
+			if done {
+				consumer.messageCh <- msg
+			}
+		},
+		onAbort: func(producerName, uuid string) {
+			consumer.messageReceived(producerName, uuid, 0, 0, nil, nil, true)
+		},
+	}
+
+	payload := createTestMessagePayload(45) // 5 chunks at ChunkMaxMessageSize=10
+	msg := &ProducerMessage{Payload: payload}
+
+	killConnectionAfter := 2 // chunks 0 and 1 are acked, then chunk 2's send fails
+	simulatedDisconnect := errors.New("connection lost")
+	dropped := false
+	pp.simulateConnectionDrop = func(chunkID int) error {
+		if !dropped && chunkID == killConnectionAfter {
+			dropped = true
+			return simulatedDisconnect
+		}
+		return nil
+	}
+
+	var finalErr error
+	pp.SendChunkedAsync(context.Background(), msg, func(chunkIndex, totalChunks int, id MessageID, err error) error {
+		if err != nil {
+			finalErr = err
+		}
+		return nil
+	})
+	assert.Equal(t, simulatedDisconnect, finalErr)
+
+	var capturedUUID string
+	pp.inFlightMu.Lock()
+	for uuid := range pp.inFlight {
+		capturedUUID = uuid
+	}
+	state, stillInFlight := pp.inFlight[capturedUUID]
+	pp.inFlightMu.Unlock()
+	assert.True(t, stillInFlight, "the interrupted send should still be tracked for resume")
+	assert.Equal(t, killConnectionAfter-1, state.lastAckedChunk)
+
+	pp.connectionReestablished()
+
+	pp.inFlightMu.Lock()
+	_, stillInFlight = pp.inFlight[capturedUUID]
+	pp.inFlightMu.Unlock()
+	assert.False(t, stillInFlight, "no uuid should remain in-flight once resume has sent every remaining chunk")
+
+	var received Message
+	select {
+	case received = <-consumer.messageCh:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the resumed send's completed message")
+	}
+	assert.Equal(t, payload, received.Payload())
+
+	select {
+	case extra := <-consumer.messageCh:
+		t.Fatalf("expected exactly one complete message, got a second: %v", extra)
+	default:
+	}
+	assert.Nil(t, mgr.get("producer-1", capturedUUID), "no orphaned uuid should remain in chunkedMsgCtxMap")
+}
+
+// TestReconnectAfterCompletedChunkedSendIsNoOp checks that a chunked send that completed without
+// error is removed from the producer's in-flight state, so a connectionReestablished that runs
+// afterwards finds nothing to resume. It runs sequentially and does not exercise concurrent
+// resume and send.
+func TestReconnectAfterCompletedChunkedSendIsNoOp(t *testing.T) {
+	producer, err := newPartitionProducer(nil, ProducerOptions{
+		Topic:               "resend-topic",
+		DisableBatching:     true,
+		EnableChunking:      true,
+		ChunkMaxMessageSize: 10,
+	})
+	assert.NoError(t, err)
+	pp := producer.(*partitionProducer)
+
+	payload := createTestMessagePayload(45)
+	msg := &ProducerMessage{Payload: payload}
+
+	pp.SendChunkedAsync(context.Background(), msg, func(chunkIndex, totalChunks int, id MessageID, err error) error {
+		return nil
+	})
+	assert.Empty(t, pp.inFlight, "a fully successful send should not remain in-flight")
+
+	// Calling connectionReestablished after the original send already completed must be a no-op:
+	// the completed uuid was deleted from p.inFlight, so there is no state to resume.
+	pp.connectionReestablished()
+	assert.Empty(t, pp.inFlight)
+}
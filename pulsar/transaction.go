@@ -0,0 +1,150 @@
+package pulsar
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Gleiphir2769/pulsar-client-go/pulsar/internal"
+)
+
+// TxnState describes the lifecycle state of a Transaction.
+type TxnState int
+
+const (
+	// TxnOpen is the state of a transaction between NewTransaction and Commit/Abort.
+	TxnOpen TxnState = iota
+	// TxnCommitted is the state after a successful Commit.
+	TxnCommitted
+	// TxnAborted is the state after a successful Abort, or after the transaction timed out.
+	TxnAborted
+)
+
+var errTransactionNotOpen = errors.New("transaction is not open")
+
+// Transaction is a handle to a Pulsar transaction, obtained via Client.NewTransaction. Producer
+// sends and consumer acknowledgements performed with this Transaction attached only become
+// visible once the transaction is committed, and are rolled back together if it is aborted.
+type Transaction interface {
+	// ID returns the transaction's coordinator-assigned identifier.
+	ID() internal.TxnID
+
+	// State returns the transaction's current lifecycle state.
+	State() TxnState
+
+	// Commit makes every send and acknowledgement performed under this transaction visible.
+	Commit() error
+
+	// Abort rolls back every send and acknowledgement performed under this transaction.
+	Abort() error
+
+	// registerSend records that the transaction has published to topic, so it is included in
+	// the coordinator's commit/abort.
+	registerSend(topic string) error
+
+	// registerAck records that the transaction has acknowledged against (topic, subscription).
+	registerAck(topic, subscription string) error
+}
+
+type transactionImpl struct {
+	mu    sync.Mutex
+	id    internal.TxnID
+	state TxnState
+	tc    *internal.TransactionCoordinatorClient
+
+	registeredPartitions    map[string]bool
+	registeredSubscriptions map[string]bool
+}
+
+func newTransaction(id internal.TxnID, tc *internal.TransactionCoordinatorClient, timeout time.Duration) Transaction {
+	t := &transactionImpl{
+		id:                      id,
+		state:                   TxnOpen,
+		tc:                      tc,
+		registeredPartitions:    make(map[string]bool),
+		registeredSubscriptions: make(map[string]bool),
+	}
+	time.AfterFunc(timeout, t.expire)
+	return t
+}
+
+func (t *transactionImpl) ID() internal.TxnID {
+	return t.id
+}
+
+func (t *transactionImpl) State() TxnState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.state
+}
+
+func (t *transactionImpl) expire() {
+	t.mu.Lock()
+	if t.state != TxnOpen {
+		t.mu.Unlock()
+		return
+	}
+	t.state = TxnAborted
+	t.mu.Unlock()
+
+	_ = t.tc.EndTxn(t.id, false)
+}
+
+func (t *transactionImpl) Commit() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.state != TxnOpen {
+		return errTransactionNotOpen
+	}
+	if err := t.tc.EndTxn(t.id, true); err != nil {
+		return err
+	}
+	t.state = TxnCommitted
+	return nil
+}
+
+func (t *transactionImpl) Abort() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.state != TxnOpen {
+		return errTransactionNotOpen
+	}
+	if err := t.tc.EndTxn(t.id, false); err != nil {
+		return err
+	}
+	t.state = TxnAborted
+	return nil
+}
+
+func (t *transactionImpl) registerSend(topic string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.state != TxnOpen {
+		return errTransactionNotOpen
+	}
+	if t.registeredPartitions[topic] {
+		return nil
+	}
+	if err := t.tc.AddPublishPartitionToTxn(t.id, topic); err != nil {
+		return err
+	}
+	t.registeredPartitions[topic] = true
+	return nil
+}
+
+func (t *transactionImpl) registerAck(topic, subscription string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.state != TxnOpen {
+		return errTransactionNotOpen
+	}
+	key := topic + "|" + subscription
+	if t.registeredSubscriptions[key] {
+		return nil
+	}
+	if err := t.tc.AddSubscriptionToTxn(t.id, topic, subscription); err != nil {
+		return err
+	}
+	t.registeredSubscriptions[key] = true
+	return nil
+}
@@ -0,0 +1,123 @@
+package pulsar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Gleiphir2769/pulsar-client-go/pulsar/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTransaction is a test-only Transaction that records registerSend/registerAck calls
+// instead of talking to a real TransactionCoordinatorClient, which currently has no broker
+// connection to talk to (see internal.ErrNotImplemented) and so can't be used to exercise this
+// path end-to-end. Like transactionImpl, it only registers a given topic/subscription once.
+type fakeTransaction struct {
+	sentTopics         []string
+	ackedSubscriptions []string
+
+	seenTopics        map[string]bool
+	seenSubscriptions map[string]bool
+}
+
+func (f *fakeTransaction) ID() internal.TxnID { return internal.TxnID{} }
+func (f *fakeTransaction) State() TxnState    { return TxnOpen }
+func (f *fakeTransaction) Commit() error      { return nil }
+func (f *fakeTransaction) Abort() error       { return nil }
+
+func (f *fakeTransaction) registerSend(topic string) error {
+	if f.seenTopics == nil {
+		f.seenTopics = make(map[string]bool)
+	}
+	if f.seenTopics[topic] {
+		return nil
+	}
+	f.seenTopics[topic] = true
+	f.sentTopics = append(f.sentTopics, topic)
+	return nil
+}
+
+func (f *fakeTransaction) registerAck(topic, subscription string) error {
+	if f.seenSubscriptions == nil {
+		f.seenSubscriptions = make(map[string]bool)
+	}
+	key := topic + "|" + subscription
+	if f.seenSubscriptions[key] {
+		return nil
+	}
+	f.seenSubscriptions[key] = true
+	f.ackedSubscriptions = append(f.ackedSubscriptions, key)
+	return nil
+}
+
+// TestTransactionalChunkedPublishRegistersEveryPartition verifies that a chunked send made with
+// a Transaction attached registers the topic with the transaction exactly once, not once per
+// chunk, and that every chunk is still reported without error.
+func TestTransactionalChunkedPublishRegistersEveryPartition(t *testing.T) {
+	producer, err := newPartitionProducer(nil, ProducerOptions{
+		Topic:               "txn-chunk-topic",
+		DisableBatching:     true,
+		EnableChunking:      true,
+		ChunkMaxMessageSize: 50,
+	})
+	assert.NoError(t, err)
+	pp := producer.(*partitionProducer)
+
+	txn := &fakeTransaction{}
+	msg := &ProducerMessage{Payload: createTestMessagePayload(230), Transaction: txn}
+
+	chunksSeen := 0
+	pp.SendChunkedAsync(context.Background(), msg, func(chunkIndex, totalChunks int, id MessageID, err error) error {
+		assert.NoError(t, err)
+		chunksSeen++
+		return nil
+	})
+
+	assert.Equal(t, 5, chunksSeen)
+	assert.Equal(t, []string{"txn-chunk-topic"}, txn.sentTopics)
+}
+
+// TestTransactionalSingleSendRegistersPartition verifies that a message small enough to go out
+// in a single send -- no chunking involved -- still registers its topic with the Transaction.
+// internalSingleSend is shared by both the chunked and non-chunked paths, but registration itself
+// happens in sendChunked before internalSingleSend is ever called, so it has to be done on both
+// of sendChunked's branches, not just the multi-chunk one.
+func TestTransactionalSingleSendRegistersPartition(t *testing.T) {
+	producer, err := newPartitionProducer(nil, ProducerOptions{
+		Topic:           "txn-chunk-topic",
+		DisableBatching: true,
+	})
+	assert.NoError(t, err)
+	pp := producer.(*partitionProducer)
+
+	txn := &fakeTransaction{}
+	msg := &ProducerMessage{Payload: createTestMessagePayload(50), Transaction: txn}
+
+	_, err = pp.Send(context.Background(), msg)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"txn-chunk-topic"}, txn.sentTopics)
+}
+
+// TestAckWithTxnRegistersSubscriptionOnce verifies that ackWithTxn registers the
+// (topic, subscription) pair with the Transaction before it would be made durable, and that
+// repeated acks against the same subscription don't re-register (fakeTransaction's dedup,
+// which mirrors transactionImpl's, exercised here through ackWithTxn's call path).
+func TestAckWithTxnRegistersSubscriptionOnce(t *testing.T) {
+	mgr := newSubscriptionChunkManager(0, 0, NewMemoryChunkStore())
+	pc := newPartitionConsumer("txn-chunk-topic", "txn-chunk-subscriber", ConsumerOptions{}, mgr)
+
+	txn := &fakeTransaction{}
+	msg := &message{id: nil, payload: []byte("hello"), topic: "txn-chunk-topic"}
+
+	assert.NoError(t, pc.ackWithTxn(msg, txn))
+	assert.NoError(t, pc.ackWithTxn(msg, txn))
+	assert.Equal(t, []string{"txn-chunk-topic|txn-chunk-subscriber"}, txn.ackedSubscriptions)
+}
+
+func TestAckWithTxnRequiresTransaction(t *testing.T) {
+	mgr := newSubscriptionChunkManager(0, 0, NewMemoryChunkStore())
+	pc := newPartitionConsumer("txn-chunk-topic", "txn-chunk-subscriber", ConsumerOptions{}, mgr)
+
+	err := pc.ackWithTxn(&message{topic: "txn-chunk-topic"}, nil)
+	assert.Error(t, err)
+}